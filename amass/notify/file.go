@@ -0,0 +1,33 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// FileSink appends each Payload, JSON-encoded, as a single line to a file.
+// It exists primarily to exercise the notify pipeline in tests without
+// standing up an HTTP endpoint.
+type FileSink struct {
+	Path string
+}
+
+// NewFileSink returns a FileSink that appends payloads to the file at path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{Path: path}
+}
+
+// Notify implements the Notifier interface.
+func (f *FileSink) Notify(p *Payload) error {
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+	return enc.Encode(p)
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader is the HTTP header carrying the HMAC-SHA256 signature of
+// the request body, allowing receivers to authenticate the payload.
+const SignatureHeader = "X-Amass-Signature"
+
+// HTTPNotifier POSTs the JSON-encoded Payload to a generic webhook URL. When
+// Secret is non-empty, the request is signed with an HMAC-SHA256 signature
+// carried in the SignatureHeader.
+type HTTPNotifier struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// NewHTTPNotifier returns an HTTPNotifier that posts to url, signing
+// requests with secret when it is non-empty.
+func NewHTTPNotifier(url, secret string) *HTTPNotifier {
+	return &HTTPNotifier{
+		URL:    url,
+		Secret: secret,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify implements the Notifier interface.
+func (h *HTTPNotifier) Notify(p *Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.Secret != "" {
+		req.Header.Set(SignatureHeader, sign(h.Secret, body))
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// sign returns the hex-encoded HMAC-SHA256 signature of body using secret.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package notify provides pluggable transports for delivering Amass tracking
+// results to external systems (SIEMs, chat tools, ticketing pipelines) as
+// they are discovered.
+package notify
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Record describes a single found/removed/moved change within a Payload.
+type Record struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Previous []string `json:"previous_addresses,omitempty"`
+	Current  []string `json:"current_addresses,omitempty"`
+	// The fields below are only populated when Type is "moved".
+	IPv4Moved     bool `json:"ipv4_moved,omitempty"`
+	IPv6Moved     bool `json:"ipv6_moved,omitempty"`
+	NetblockMoved bool `json:"netblock_moved,omitempty"`
+	ASNMoved      bool `json:"asn_moved,omitempty"`
+}
+
+// Payload is the body delivered to a Notifier whenever a tracking pass
+// produces one or more change records for a domain.
+type Payload struct {
+	Domain    string    `json:"domain"`
+	Earliest1 time.Time `json:"earliest1"`
+	Latest1   time.Time `json:"latest1"`
+	Earliest2 time.Time `json:"earliest2"`
+	Latest2   time.Time `json:"latest2"`
+	Records   []Record  `json:"records"`
+}
+
+// Notifier delivers a Payload to an external system. Implementations are
+// expected to be safe for repeated, sequential use.
+type Notifier interface {
+	Notify(p *Payload) error
+}
+
+// Backoff controls how Send retries a delivery attempt that returns an error.
+type Backoff struct {
+	MaxRetries int
+	Initial    time.Duration
+	Max        time.Duration
+}
+
+// DefaultBackoff is used by Send when no Backoff is provided.
+var DefaultBackoff = Backoff{
+	MaxRetries: 3,
+	Initial:    time.Second,
+	Max:        30 * time.Second,
+}
+
+// Send delivers p through n, retrying on error with jittered exponential
+// backoff, and returns the last error encountered if every attempt fails.
+func Send(n Notifier, p *Payload, b *Backoff) error {
+	if b == nil {
+		b = &DefaultBackoff
+	}
+
+	wait := b.Initial
+	var err error
+	for attempt := 0; attempt <= b.MaxRetries; attempt++ {
+		if err = n.Notify(p); err == nil {
+			return nil
+		}
+		if attempt == b.MaxRetries {
+			break
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(wait) / 2))
+		time.Sleep(wait + jitter)
+
+		wait *= 2
+		if wait > b.Max {
+			wait = b.Max
+		}
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingNotifier fails its first failures attempts, then succeeds.
+type countingNotifier struct {
+	failures int
+	attempts int
+}
+
+func (c *countingNotifier) Notify(p *Payload) error {
+	c.attempts++
+	if c.attempts <= c.failures {
+		return errors.New("simulated delivery failure")
+	}
+	return nil
+}
+
+func testBackoff() *Backoff {
+	return &Backoff{MaxRetries: 3, Initial: time.Millisecond, Max: 4 * time.Millisecond}
+}
+
+func TestSendRetriesUntilSuccess(t *testing.T) {
+	n := &countingNotifier{failures: 2}
+
+	if err := Send(n, &Payload{Domain: "owasp.org"}, testBackoff()); err != nil {
+		t.Fatalf("Send returned an error after a recoverable number of failures: %v", err)
+	}
+	if n.attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", n.attempts)
+	}
+}
+
+func TestSendGivesUpAfterMaxRetries(t *testing.T) {
+	b := testBackoff()
+	n := &countingNotifier{failures: b.MaxRetries + 1}
+
+	if err := Send(n, &Payload{Domain: "owasp.org"}, b); err == nil {
+		t.Fatal("Send did not return an error after exhausting all retries")
+	}
+	if want := b.MaxRetries + 1; n.attempts != want {
+		t.Errorf("expected %d attempts, got %d", want, n.attempts)
+	}
+}
+
+func TestSignIsDeterministic(t *testing.T) {
+	body := []byte(`{"domain":"owasp.org"}`)
+
+	sig1 := sign("shared-secret", body)
+	sig2 := sign("shared-secret", body)
+	if sig1 != sig2 {
+		t.Errorf("sign produced different output for identical input: %s != %s", sig1, sig2)
+	}
+
+	if other := sign("different-secret", body); other == sig1 {
+		t.Error("sign produced the same signature for two different secrets")
+	}
+}
+
+func TestHTTPNotifierSignsAndDeliversPayload(t *testing.T) {
+	payload := &Payload{Domain: "owasp.org", Records: []Record{{Type: "found", Name: "www.owasp.org"}}}
+
+	var gotBody []byte
+	var gotSig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotBody, _ = ioutil.ReadAll(req.Body)
+		gotSig = req.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	h := NewHTTPNotifier(srv.URL, "shared-secret")
+	if err := h.Notify(payload); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+
+	var decoded Payload
+	if err := json.Unmarshal(gotBody, &decoded); err != nil {
+		t.Fatalf("failed to decode delivered payload: %v", err)
+	}
+	if decoded.Domain != payload.Domain {
+		t.Errorf("expected domain %q, got %q", payload.Domain, decoded.Domain)
+	}
+
+	if want := sign("shared-secret", gotBody); gotSig != want {
+		t.Errorf("signature header %q did not match the expected HMAC %q", gotSig, want)
+	}
+}
+
+func TestHTTPNotifierErrorsOnNonSuccessStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	h := NewHTTPNotifier(srv.URL, "")
+	if err := h.Notify(&Payload{Domain: "owasp.org"}); err == nil {
+		t.Error("expected an error for a non-2xx response, got nil")
+	}
+}
+
+func TestFileSinkRoundTripsPayload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify-test")
+	if err != nil {
+		t.Fatalf("failed to create a temp directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "diffs.jsonl")
+	f := NewFileSink(path)
+
+	payload := &Payload{Domain: "owasp.org", Records: []Record{{Type: "removed", Name: "old.owasp.org"}}}
+	if err := f.Notify(payload); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the sink file: %v", err)
+	}
+
+	var decoded Payload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode the persisted payload: %v", err)
+	}
+	if decoded.Domain != payload.Domain || len(decoded.Records) != len(payload.Records) {
+		t.Errorf("decoded payload %+v did not match the original %+v", decoded, payload)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 {
+		t.Error("expected the sink file to be non-empty")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SlackNotifier posts a human-readable summary of a Payload to a Slack
+// incoming webhook URL.
+type SlackNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewSlackNotifier returns a SlackNotifier that posts to a Slack incoming
+// webhook at url.
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify implements the Notifier interface.
+func (s *SlackNotifier) Notify(p *Payload) error {
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: summarize(p)})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.Client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %s", resp.Status)
+	}
+	return nil
+}
+
+func summarize(p *Payload) string {
+	msg := fmt.Sprintf("Amass tracking detected %d change(s) for *%s*:\n", len(p.Records), p.Domain)
+	for _, rec := range p.Records {
+		switch rec.Type {
+		case "removed":
+			msg += fmt.Sprintf("- Removed `%s`\n", rec.Name)
+		case "moved":
+			msg += fmt.Sprintf("- Moved `%s`\n", rec.Name)
+		default:
+			msg += fmt.Sprintf("- Found `%s`\n", rec.Name)
+		}
+	}
+	return msg
+}
@@ -4,19 +4,24 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"math/rand"
 	"os"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/OWASP/Amass/amass"
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/handlers"
+	"github.com/OWASP/Amass/amass/notify"
 	"github.com/fatih/color"
 )
 
@@ -39,13 +44,26 @@ var (
 	green  = color.New(color.FgHiGreen).SprintFunc()
 	blue   = color.New(color.FgHiBlue).SprintFunc()
 	// Command-line switches and provided parameters
-	help     = flag.Bool("h", false, "Show the program usage message")
-	list     = flag.Bool("list", false, "Print information for all available enumerations")
-	vprint   = flag.Bool("version", false, "Print the version number of this Amass binary")
-	dir      = flag.String("dir", "", "Path to the directory containing the graph database")
-	all      = flag.Bool("all", false, "Include all enumerations in the tracking")
-	last     = flag.Int("last", 2, "The number of recent enumerations to include in the tracking")
-	startStr = flag.String("start", "", "Exclude all enumerations before (format: "+timeFormat+")")
+	help          = flag.Bool("h", false, "Show the program usage message")
+	list          = flag.Bool("list", false, "Print information for all available enumerations")
+	vprint        = flag.Bool("version", false, "Print the version number of this Amass binary")
+	dir           = flag.String("dir", "", "Path to the directory containing the graph database")
+	all           = flag.Bool("all", false, "Include all enumerations in the tracking")
+	last          = flag.Int("last", 2, "The number of recent enumerations to include in the tracking")
+	startStr      = flag.String("start", "", "Exclude all enumerations before (format: "+timeFormat+")")
+	jsonOut       = flag.Bool("json", false, "Print the tracking results as JSON")
+	csvOut        = flag.Bool("csv", false, "Print the tracking results as CSV")
+	domainsFile   = flag.String("df", "", "Path to a file providing root domain names")
+	webhook       = flag.String("webhook", "", "URL (or, with -webhook-kind file, a path) to receive diff notifications")
+	webhookKind   = flag.String("webhook-kind", "generic", "Webhook transport: generic, slack, or file")
+	webhookSecret = flag.String("webhook-secret", "", "Shared secret used to HMAC-SHA256 sign webhook payloads (generic transport only)")
+	watch         = flag.Duration("watch", 0, "Re-enumerate on this interval and report only new changes (e.g. 1h)")
+	jitter        = flag.Duration("jitter", 0, "Randomize the watch interval by up to this amount")
+	maxRuns       = flag.Int("max-runs", 0, "Stop watching after this many enumerations (0 means unlimited)")
+	onlyNew       = flag.Bool("only-new", false, "Only report found (new) names")
+	onlyRemoved   = flag.Bool("only-removed", false, "Only report removed names")
+	onlyMoved     = flag.Bool("only-moved", false, "Only report moved names")
+	minSeverity   = flag.String("min-severity", "low", "Minimum move severity to report: low, netblock, or provider")
 )
 
 func main() {
@@ -71,6 +89,15 @@ func main() {
 		fmt.Fprintf(color.Error, "version %s\n", amass.Version)
 		return
 	}
+	if *domainsFile != "" {
+		fileDomains, err := readWordList(*domainsFile)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to read the domain names file: %v\n", err)
+			return
+		}
+		domains = append(domains, fileDomains...)
+	}
+	domains = uniqueStrings(domains)
 	if len(domains) == 0 {
 		r.Fprintln(color.Error, "No root domain names were provided")
 		return
@@ -79,6 +106,22 @@ func main() {
 		r.Fprintln(color.Error, "The start flag cannot be used with the last or all flags")
 		return
 	}
+	if *jsonOut && *csvOut {
+		r.Fprintln(color.Error, "The json and csv flags cannot be used together")
+		return
+	}
+	if *watch > 0 && (*list || *all || *startStr != "") {
+		r.Fprintln(color.Error, "The watch flag cannot be used with the list, all, or start flags")
+		return
+	}
+	if *minSeverity != "low" && *minSeverity != "netblock" && *minSeverity != "provider" {
+		r.Fprintln(color.Error, "The min-severity flag must be one of: low, netblock, provider")
+		return
+	}
+	if *webhookKind != "generic" && *webhookKind != "slack" && *webhookKind != "file" {
+		r.Fprintln(color.Error, "The webhook-kind flag must be one of: generic, slack, file")
+		return
+	}
 
 	var err error
 	var start time.Time
@@ -108,10 +151,27 @@ func main() {
 		return
 	}
 
+	var notifier notify.Notifier
+	if *webhook != "" {
+		switch *webhookKind {
+		case "slack":
+			notifier = notify.NewSlackNotifier(*webhook)
+		case "file":
+			notifier = notify.NewFileSink(*webhook)
+		default:
+			notifier = notify.NewHTTPNotifier(*webhook, *webhookSecret)
+		}
+	}
+
+	if *watch > 0 {
+		runWatch(domains, graph, notifier, *watch, *jitter, *maxRuns)
+		return
+	}
+
 	var enums []string
-	// Obtain the enumerations that include the provided domain
+	// Obtain the enumerations that include any of the provided domains
 	for _, e := range graph.EnumerationList() {
-		if enumContainsDomain(e, domains[0], graph) {
+		if enumContainsAnyDomain(e, domains, graph) {
 			enums = append(enums, e)
 		}
 	}
@@ -153,23 +213,54 @@ func main() {
 	}
 
 	var prev string
+	var allDiffs []*TrackerDiff
 	for i, enum := range enums {
 		if prev == "" {
 			prev = enum
 			continue
 		}
 
-		fmt.Fprintf(color.Output, "%s\t%s%s%s\n%s\t%s%s%s\n\n", blue("Between"),
-			yellow(earliest[i-1].Format(timeFormat)), blue(" -> "), yellow(latest[i-1].Format(timeFormat)),
-			blue("and"), yellow(earliest[i].Format(timeFormat)), blue(" -> "), yellow(latest[i].Format(timeFormat)))
+		if !*jsonOut && !*csvOut {
+			fmt.Fprintf(color.Output, "%s\t%s%s%s\n%s\t%s%s%s\n\n", blue("Between"),
+				yellow(earliest[i-1].Format(timeFormat)), blue(" -> "), yellow(latest[i-1].Format(timeFormat)),
+				blue("and"), yellow(earliest[i].Format(timeFormat)), blue(" -> "), yellow(latest[i].Format(timeFormat)))
+		}
+
+		for _, domain := range domains {
+			out1 := getEnumDataInScope(domain, prev, graph)
+			out2 := getEnumDataInScope(domain, enum, graph)
+			diffs := diffEnumOutput(domain, out1, out2,
+				earliest[i-1], latest[i-1], earliest[i], latest[i])
+			diffs = filterDiffs(diffs, *onlyNew, *onlyRemoved, *onlyMoved, *minSeverity)
 
-		out1 := getEnumDataInScope(domains[0], prev, graph)
-		out2 := getEnumDataInScope(domains[0], enum, graph)
-		for _, d := range diffEnumOutput(domains[0], out1, out2) {
-			fmt.Fprintln(color.Output, d)
+			if len(diffs) == 0 {
+				continue
+			}
+
+			if notifier != nil {
+				if err := notify.Send(notifier, diffsToPayload(domain, diffs), nil); err != nil {
+					r.Fprintf(color.Error, "Failed to deliver the webhook notification: %v\n", err)
+				}
+			}
+
+			if *jsonOut || *csvOut {
+				allDiffs = append(allDiffs, diffs...)
+				continue
+			}
+
+			fmt.Fprintf(color.Output, "%s\n", blue(domain))
+			for _, d := range diffs {
+				fmt.Fprintln(color.Output, d.String())
+			}
 		}
 		prev = enum
 	}
+
+	if *jsonOut {
+		printJSON(allDiffs)
+	} else if *csvOut {
+		printCSV(allDiffs)
+	}
 }
 
 func getEnumDataInScope(domain, enum string, h handlers.DataHandler) []*core.Output {
@@ -183,7 +274,43 @@ func getEnumDataInScope(domain, enum string, h handlers.DataHandler) []*core.Out
 	return out
 }
 
-func diffEnumOutput(domain string, eout1, eout2 []*core.Output) []string {
+// TrackerDiff is a single machine-parseable record describing a change
+// detected between two enumerations of the same domain.
+type TrackerDiff struct {
+	Type      string    `json:"type"`
+	Domain    string    `json:"domain"`
+	Name      string    `json:"name"`
+	Previous  []string  `json:"previous_addresses,omitempty"`
+	Current   []string  `json:"current_addresses,omitempty"`
+	Earliest1 time.Time `json:"earliest1"`
+	Latest1   time.Time `json:"latest1"`
+	Earliest2 time.Time `json:"earliest2"`
+	Latest2   time.Time `json:"latest2"`
+	// The fields below are only populated when Type is "moved".
+	IPv4Moved     bool `json:"ipv4_moved,omitempty"`
+	IPv6Moved     bool `json:"ipv6_moved,omitempty"`
+	NetblockMoved bool `json:"netblock_moved,omitempty"`
+	ASNMoved      bool `json:"asn_moved,omitempty"`
+}
+
+// String renders the diff the same way the tracker has always printed it
+// to the terminal, for use when -json and -csv are not requested.
+func (d *TrackerDiff) String() string {
+	switch d.Type {
+	case "removed":
+		return fmt.Sprintf("%s%s %s", blue("Removed: "),
+			green(d.Name), yellow(strings.Join(d.Previous, ",")))
+	case "moved":
+		return fmt.Sprintf("%s%s\n\t%s\t%s\n\t%s\t%s", blue("Moved: "),
+			green(d.Name), blue(" from "), yellow(strings.Join(d.Previous, ",")),
+			blue(" to "), yellow(strings.Join(d.Current, ",")))
+	default:
+		return fmt.Sprintf("%s%s %s", blue("Found: "),
+			green(d.Name), yellow(strings.Join(d.Current, ",")))
+	}
+}
+
+func diffEnumOutput(domain string, eout1, eout2 []*core.Output, e1, l1, e2, l2 time.Time) []*TrackerDiff {
 	emap1 := make(map[string]*core.Output)
 	emap2 := make(map[string]*core.Output)
 
@@ -194,22 +321,35 @@ func diffEnumOutput(domain string, eout1, eout2 []*core.Output) []string {
 		emap2[o.Name] = o
 	}
 
+	newDiff := func(t, name string, prev, cur []core.AddressInfo) *TrackerDiff {
+		return &TrackerDiff{
+			Type:      t,
+			Domain:    domain,
+			Name:      name,
+			Previous:  addressStrings(prev),
+			Current:   addressStrings(cur),
+			Earliest1: e1,
+			Latest1:   l1,
+			Earliest2: e2,
+			Latest2:   l2,
+		}
+	}
+
 	handled := make(map[string]struct{})
-	var diff []string
+	var diff []*TrackerDiff
 	for _, o := range eout1 {
 		handled[o.Name] = struct{}{}
 
 		if _, found := emap2[o.Name]; !found {
-			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Removed: "),
-				green(o.Name), yellow(lineOfAddresses(o.Addresses))))
+			diff = append(diff, newDiff("removed", o.Name, o.Addresses, nil))
 			continue
 		}
 
 		o2 := emap2[o.Name]
 		if !compareAddresses(o.Addresses, o2.Addresses) {
-			diff = append(diff, fmt.Sprintf("%s%s\n\t%s\t%s\n\t%s\t%s", blue("Moved: "),
-				green(o.Name), blue(" from "), yellow(lineOfAddresses(o.Addresses)),
-				blue(" to "), yellow(lineOfAddresses(o2.Addresses))))
+			d := newDiff("moved", o.Name, o.Addresses, o2.Addresses)
+			classifyMove(d, o.Addresses, o2.Addresses)
+			diff = append(diff, d)
 		}
 	}
 
@@ -219,23 +359,191 @@ func diffEnumOutput(domain string, eout1, eout2 []*core.Output) []string {
 		}
 
 		if _, found := emap1[o.Name]; !found {
-			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Found: "),
-				green(o.Name), yellow(lineOfAddresses(o.Addresses))))
+			diff = append(diff, newDiff("found", o.Name, nil, o.Addresses))
 		}
 	}
 	return diff
 }
 
-func lineOfAddresses(addrs []core.AddressInfo) string {
-	var line string
+func addressStrings(addrs []core.AddressInfo) []string {
+	var lines []string
+
+	for _, addr := range addrs {
+		lines = append(lines, addr.Address.String())
+	}
+	return lines
+}
+
+// diffsToPayload converts the diffs detected for a single domain into the
+// payload shape delivered to a notify.Notifier.
+func diffsToPayload(domain string, diffs []*TrackerDiff) *notify.Payload {
+	p := &notify.Payload{
+		Domain:  domain,
+		Records: make([]notify.Record, len(diffs)),
+	}
+	if len(diffs) > 0 {
+		p.Earliest1 = diffs[0].Earliest1
+		p.Latest1 = diffs[0].Latest1
+		p.Earliest2 = diffs[0].Earliest2
+		p.Latest2 = diffs[0].Latest2
+	}
+	for i, d := range diffs {
+		p.Records[i] = notify.Record{
+			Type:          d.Type,
+			Name:          d.Name,
+			Previous:      d.Previous,
+			Current:       d.Current,
+			IPv4Moved:     d.IPv4Moved,
+			IPv6Moved:     d.IPv6Moved,
+			NetblockMoved: d.NetblockMoved,
+			ASNMoved:      d.ASNMoved,
+		}
+	}
+	return p
+}
+
+// runWatch loops, triggering a fresh enumeration on each pass, diffing it
+// against the previous enumeration, and reporting (and optionally notifying)
+// only the changes found since the last pass. It returns once maxRuns passes
+// have completed, or runs indefinitely when maxRuns is 0.
+func runWatch(domains []string, graph handlers.DataHandler, notifier notify.Notifier, interval, jitter time.Duration, maxRuns int) {
+	for run := 0; maxRuns <= 0 || run < maxRuns; run++ {
+		enumID, err := runEnumeration(domains, graph)
+		if err != nil {
+			r.Fprintf(color.Error, "Enumeration failed: %v\n", err)
+		} else {
+			reportWatchChanges(domains, graph, notifier, enumID)
+		}
+
+		if maxRuns > 0 && run+1 >= maxRuns {
+			break
+		}
 
-	for i, addr := range addrs {
-		if i != 0 {
-			line = line + ","
+		wait := interval
+		if jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(jitter)))
 		}
-		line = line + addr.Address.String()
+		time.Sleep(wait)
+	}
+}
+
+// reportWatchChanges diffs the enumeration identified by enumID against the
+// one immediately before it and prints (and optionally delivers via
+// notifier) only the resulting changes, grouped by domain.
+func reportWatchChanges(domains []string, graph handlers.DataHandler, notifier notify.Notifier, enumID string) {
+	var enums []string
+	for _, e := range graph.EnumerationList() {
+		if enumContainsAnyDomain(e, domains, graph) {
+			enums = append(enums, e)
+		}
+	}
+	enums, earliest, latest := orderedEnumsAndDateRanges(enums, graph)
+	if len(enums) < 2 {
+		return
+	}
+
+	prev := enums[len(enums)-2]
+	e1, l1 := earliest[len(enums)-2], latest[len(enums)-2]
+	e2, l2 := earliest[len(enums)-1], latest[len(enums)-1]
+
+	var allDiffs []*TrackerDiff
+	for _, domain := range domains {
+		out1 := getEnumDataInScope(domain, prev, graph)
+		out2 := getEnumDataInScope(domain, enumID, graph)
+		diffs := diffEnumOutput(domain, out1, out2, e1, l1, e2, l2)
+		diffs = filterDiffs(diffs, *onlyNew, *onlyRemoved, *onlyMoved, *minSeverity)
+		if len(diffs) == 0 {
+			continue
+		}
+
+		if notifier != nil {
+			if err := notify.Send(notifier, diffsToPayload(domain, diffs), nil); err != nil {
+				r.Fprintf(color.Error, "Failed to deliver the webhook notification: %v\n", err)
+			}
+		}
+
+		if *jsonOut || *csvOut {
+			allDiffs = append(allDiffs, diffs...)
+			continue
+		}
+
+		fmt.Fprintf(color.Output, "%s\n", blue(domain))
+		for _, d := range diffs {
+			fmt.Fprintln(color.Output, d.String())
+		}
+	}
+
+	if *jsonOut {
+		printJSON(allDiffs)
+	} else if *csvOut {
+		printCSV(allDiffs)
+	}
+}
+
+// runEnumeration performs a single fresh amass.Enumeration of domains,
+// persisting every discovered result into graph, and returns the new
+// enumeration's identifier.
+func runEnumeration(domains []string, graph handlers.DataHandler) (string, error) {
+	e := amass.NewEnumeration()
+	e.Config.AddDomains(domains)
+
+	enumID := fmt.Sprintf("watch-%d", time.Now().UnixNano())
+	done := make(chan struct{})
+	var insertErr error
+	go func() {
+		for o := range e.Output {
+			if err := graph.Insert(enumID, o); err != nil {
+				r.Fprintf(color.Error, "Failed to insert a result into the graph database: %v\n", err)
+				insertErr = err
+			}
+		}
+		close(done)
+	}()
+
+	err := e.Start()
+	<-done
+	if err == nil {
+		err = insertErr
+	}
+	return enumID, err
+}
+
+func printJSON(diffs []*TrackerDiff) {
+	if diffs == nil {
+		diffs = []*TrackerDiff{}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(diffs); err != nil {
+		r.Fprintf(color.Error, "Failed to encode the JSON output: %v\n", err)
+	}
+}
+
+func printCSV(diffs []*TrackerDiff) {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	w.Write([]string{"type", "domain", "name", "previous_addresses",
+		"current_addresses", "earliest1", "latest1", "earliest2", "latest2",
+		"ipv4_moved", "ipv6_moved", "netblock_moved", "asn_moved"})
+	for _, d := range diffs {
+		w.Write([]string{
+			d.Type,
+			d.Domain,
+			d.Name,
+			strings.Join(d.Previous, ","),
+			strings.Join(d.Current, ","),
+			d.Earliest1.Format(timeFormat),
+			d.Latest1.Format(timeFormat),
+			d.Earliest2.Format(timeFormat),
+			d.Latest2.Format(timeFormat),
+			strconv.FormatBool(d.IPv4Moved),
+			strconv.FormatBool(d.IPv6Moved),
+			strconv.FormatBool(d.NetblockMoved),
+			strconv.FormatBool(d.ASNMoved),
+		})
 	}
-	return line
 }
 
 func compareAddresses(addr1, addr2 []core.AddressInfo) bool {
@@ -255,6 +563,122 @@ func compareAddresses(addr1, addr2 []core.AddressInfo) bool {
 	return true
 }
 
+// classifyMove inspects the address sets on both sides of a move and tags d
+// with which facets actually changed, so churn can be filtered by severity:
+// a host flipping between addresses in the same netblock/ASN is routine,
+// while a netblock or ASN change suggests the asset moved providers.
+func classifyMove(d *TrackerDiff, before, after []core.AddressInfo) {
+	beforeV4 := make(map[string]struct{})
+	afterV4 := make(map[string]struct{})
+	beforeV6 := make(map[string]struct{})
+	afterV6 := make(map[string]struct{})
+	beforeNets := make(map[string]struct{})
+	afterNets := make(map[string]struct{})
+	beforeASNs := make(map[int]struct{})
+	afterASNs := make(map[int]struct{})
+
+	for _, a := range before {
+		if a.Address.To4() != nil {
+			beforeV4[a.Address.String()] = struct{}{}
+		} else {
+			beforeV6[a.Address.String()] = struct{}{}
+		}
+		if a.Netblock != nil {
+			beforeNets[a.Netblock.String()] = struct{}{}
+		}
+		beforeASNs[a.ASN] = struct{}{}
+	}
+	for _, a := range after {
+		if a.Address.To4() != nil {
+			afterV4[a.Address.String()] = struct{}{}
+		} else {
+			afterV6[a.Address.String()] = struct{}{}
+		}
+		if a.Netblock != nil {
+			afterNets[a.Netblock.String()] = struct{}{}
+		}
+		afterASNs[a.ASN] = struct{}{}
+	}
+
+	d.IPv4Moved = !sameStringSets(beforeV4, afterV4)
+	d.IPv6Moved = !sameStringSets(beforeV6, afterV6)
+	d.NetblockMoved = !sameStringSets(beforeNets, afterNets)
+	d.ASNMoved = !sameIntSets(beforeASNs, afterASNs)
+}
+
+func sameStringSets(a, b map[string]struct{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if _, found := b[k]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+func sameIntSets(a, b map[int]struct{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if _, found := b[k]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// severityAllows reports whether a moved diff meets the -min-severity
+// threshold: "netblock" requires at least a netblock change, "provider"
+// requires an ASN (i.e. likely provider) change.
+func severityAllows(d *TrackerDiff, minSeverity string) bool {
+	if d.Type != "moved" {
+		return true
+	}
+	switch minSeverity {
+	case "netblock":
+		return d.NetblockMoved || d.ASNMoved
+	case "provider":
+		return d.ASNMoved
+	default:
+		return true
+	}
+}
+
+// filterDiffs applies the -only-new/-only-removed/-only-moved type filters
+// (composable; no filter set means every type passes) and the -min-severity
+// threshold for moves.
+func filterDiffs(diffs []*TrackerDiff, onlyNew, onlyRemoved, onlyMoved bool, minSeverity string) []*TrackerDiff {
+	anyOnly := onlyNew || onlyRemoved || onlyMoved
+
+	var filtered []*TrackerDiff
+	for _, d := range diffs {
+		if anyOnly {
+			switch d.Type {
+			case "found":
+				if !onlyNew {
+					continue
+				}
+			case "removed":
+				if !onlyRemoved {
+					continue
+				}
+			case "moved":
+				if !onlyMoved {
+					continue
+				}
+			}
+		}
+		if !severityAllows(d, minSeverity) {
+			continue
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
 func orderedEnumsAndDateRanges(enums []string, h handlers.DataHandler) ([]string, []time.Time, []time.Time) {
 	sort.Slice(enums, func(i, j int) bool {
 		var less bool
@@ -277,16 +701,48 @@ func orderedEnumsAndDateRanges(enums []string, h handlers.DataHandler) ([]string
 	return enums, earliest, latest
 }
 
-func enumContainsDomain(enum, domain string, h handlers.DataHandler) bool {
-	var found bool
-
+func enumContainsAnyDomain(enum string, domains []string, h handlers.DataHandler) bool {
 	for _, d := range h.EnumerationDomains(enum) {
-		if d == domain {
-			found = true
-			break
+		for _, domain := range domains {
+			if d == domain {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readWordList reads one entry per line from the file at path, mirroring the
+// domain/wordlist file handling in the main amass binary.
+func readWordList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func uniqueStrings(strs []string) []string {
+	var unique []string
+
+	seen := make(map[string]struct{})
+	for _, s := range strs {
+		if _, found := seen[s]; !found {
+			seen[s] = struct{}{}
+			unique = append(unique, s)
 		}
 	}
-	return found
+	return unique
 }
 
 func printBanner() {